@@ -0,0 +1,26 @@
+package audit
+
+import "time"
+
+// Record is one audited request/response pair. Prompt and Response are left
+// empty unless full-body logging is turned on, since they can contain
+// sensitive user content.
+type Record struct {
+	Timestamp        time.Time `json:"timestamp"`
+	AuthHash         string    `json:"auth_hash"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	LatencyMS        int64     `json:"latency_ms"`
+	CacheStatus      string    `json:"cache_status"`
+	HTTPStatus       int       `json:"http_status"`
+	RequestBodySHA   string    `json:"request_body_sha"`
+	Prompt           string    `json:"prompt,omitempty"`
+	Response         string    `json:"response,omitempty"`
+}
+
+// Sink persists audit Records. Implementations must be safe for concurrent
+// use, since Write is called from every request's handler goroutine.
+type Sink interface {
+	Write(rec Record) error
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteSink persists audit records to a SQLite database, indexed by model
+// and by day so cost can be queried by either without a full table scan.
+type SQLiteSink struct {
+	db *sql.DB
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS audit_log (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp         TEXT NOT NULL,
+	auth_hash         TEXT NOT NULL,
+	model             TEXT NOT NULL,
+	prompt_tokens     INTEGER NOT NULL,
+	completion_tokens INTEGER NOT NULL,
+	latency_ms        INTEGER NOT NULL,
+	cache_status      TEXT NOT NULL,
+	http_status       INTEGER NOT NULL,
+	request_body_sha  TEXT NOT NULL,
+	prompt            TEXT,
+	response          TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_model ON audit_log (model);
+CREATE INDEX IF NOT EXISTS idx_audit_log_day ON audit_log (substr(timestamp, 1, 10));
+`
+
+// NewSQLiteSink opens (creating if necessary) the database at path and
+// ensures the audit_log schema exists.
+func NewSQLiteSink(path string) (*SQLiteSink, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create audit schema: %w", err)
+	}
+	return &SQLiteSink{db: db}, nil
+}
+
+func (s *SQLiteSink) Write(rec Record) error {
+	_, err := s.db.Exec(
+		`INSERT INTO audit_log
+			(timestamp, auth_hash, model, prompt_tokens, completion_tokens, latency_ms, cache_status, http_status, request_body_sha, prompt, response)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rec.Timestamp.UTC().Format(time.RFC3339Nano),
+		rec.AuthHash,
+		rec.Model,
+		rec.PromptTokens,
+		rec.CompletionTokens,
+		rec.LatencyMS,
+		rec.CacheStatus,
+		rec.HTTPStatus,
+		rec.RequestBodySHA,
+		rec.Prompt,
+		rec.Response,
+	)
+	return err
+}
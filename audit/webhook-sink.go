@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds how long a single POST waits for the remote
+// endpoint, so a slow or unreachable webhook can't hang onto the
+// connection (and the goroutine dispatching it) forever.
+const webhookTimeout = 5 * time.Second
+
+// WebhookSink POSTs each Record as JSON to a configured URL, for piping
+// audit data into an external logging or billing system. Write dispatches
+// the POST on its own goroutine instead of blocking the caller: it's
+// called from every request's handler goroutine, and a request shouldn't
+// stall waiting on a third-party endpoint just to record that it happened.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a WebhookSink that POSTs to url.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+func (s *WebhookSink) Write(rec Record) error {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			log.Println("Error posting audit record to webhook:", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			log.Println("Audit webhook returned status", resp.StatusCode)
+		}
+	}()
+	return nil
+}
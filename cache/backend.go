@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"net/http"
+	"time"
+)
+
+// Entry is a single cached HTTP response.
+type Entry struct {
+	Body      []byte
+	Headers   http.Header
+	ExpiresAt time.Time
+}
+
+// Expired reports whether the entry's TTL has elapsed. A zero ExpiresAt
+// means the entry never expires.
+func (e Entry) Expired() bool {
+	return !e.ExpiresAt.IsZero() && time.Now().After(e.ExpiresAt)
+}
+
+// Blob is a handle to a cached response body that doesn't need to be held
+// in memory all at once, so a multi-MB response can be streamed back to a
+// client straight off disk (or another backing store).
+type Blob interface {
+	ReadAt(p []byte, off int64) (int, error)
+	Size() int64
+	Close() error
+}
+
+// EvictReason explains why OnEvict fired for a given key.
+type EvictReason int
+
+const (
+	EvictReasonCapacity EvictReason = iota
+	EvictReasonTTL
+	EvictReasonDelete
+)
+
+// EvictFunc is invoked whenever a backend removes an entry, whether due to
+// capacity pressure, TTL expiry, or an explicit Delete.
+type EvictFunc func(key string, reason EvictReason)
+
+// Metrics tracks cache effectiveness for a Backend.
+type Metrics struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// Backend is the storage strategy behind ResponseCache. Implementations may
+// keep entries in memory, on disk, or in a shared store such as Redis so
+// that multiple proxy replicas can share a cache.
+type Backend interface {
+	// Get retrieves a non-streaming response from the cache.
+	Get(key string) (Entry, bool)
+	// Set stores a non-streaming response in the cache.
+	Set(key string, entry Entry) error
+	// SetStream initializes a streaming response in the cache, recording
+	// headers (notably Content-Type) alongside the chunks so a later
+	// replay can restore them.
+	SetStream(key string, bufferSize int, headers http.Header) *StreamResponse
+	// GetStream retrieves a streaming response from the cache.
+	GetStream(key string) (*StreamResponse, bool)
+	// Delete removes key from the cache, if present.
+	Delete(key string)
+	// Metrics reports cumulative hit/miss/eviction counts.
+	Metrics() Metrics
+}
+
+// BlobBackend is an optional capability a Backend can implement when it
+// can hand back a cached body as a Blob instead of reading it fully into
+// memory, so a large response can be streamed straight to a client.
+// DiskBackend implements this; MemoryBackend and RedisBackend don't need
+// to, since they already hold entries in memory or over the wire.
+type BlobBackend interface {
+	// GetBlob retrieves a non-streaming response as a Blob. The returned
+	// Entry's Body is always nil; the body lives in the Blob instead.
+	GetBlob(key string) (Entry, Blob, bool)
+}
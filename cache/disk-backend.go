@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// fileBlob is a Blob backed by a file on disk.
+type fileBlob struct {
+	f    *os.File
+	size int64
+}
+
+func (fb *fileBlob) ReadAt(p []byte, off int64) (int, error) { return fb.f.ReadAt(p, off) }
+func (fb *fileBlob) Size() int64                             { return fb.size }
+func (fb *fileBlob) Close() error                            { return fb.f.Close() }
+
+type diskMeta struct {
+	Headers   http.Header
+	ExpiresAt time.Time
+	Size      int64
+}
+
+// DiskBackendOptions configures a DiskBackend.
+type DiskBackendOptions struct {
+	// Dir is the directory response bodies are streamed to. It is created
+	// if it doesn't already exist.
+	Dir string
+	// DefaultTTL is applied to entries set without an explicit ExpiresAt.
+	DefaultTTL time.Duration
+	// OnEvict, if set, is called whenever an entry is removed.
+	OnEvict EvictFunc
+}
+
+// DiskBackend streams response bodies to files under Dir instead of
+// keeping them resident in memory, which matters for large multi-MB
+// responses. Metadata (headers, expiry) is kept in memory alongside the
+// file path and is not reloaded on startup, so files written by an
+// earlier process are not served after a restart.
+type DiskBackend struct {
+	streamStore
+
+	mu   sync.RWMutex
+	opts DiskBackendOptions
+	meta map[string]diskMeta
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// NewDiskBackend creates a DiskBackend rooted at opts.Dir.
+func NewDiskBackend(opts DiskBackendOptions) (*DiskBackend, error) {
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskBackend{
+		streamStore: newStreamStore(opts.DefaultTTL, 0),
+		opts:        opts,
+		meta:        make(map[string]diskMeta),
+	}, nil
+}
+
+func (b *DiskBackend) pathFor(key string) string {
+	return filepath.Join(b.opts.Dir, hex.EncodeToString([]byte(key))+".blob")
+}
+
+func (b *DiskBackend) Set(key string, entry Entry) error {
+	if entry.ExpiresAt.IsZero() && b.opts.DefaultTTL > 0 {
+		entry.ExpiresAt = time.Now().Add(b.opts.DefaultTTL)
+	}
+
+	f, err := os.Create(b.pathFor(key))
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(entry.Body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.meta[key] = diskMeta{Headers: entry.Headers, ExpiresAt: entry.ExpiresAt, Size: int64(len(entry.Body))}
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *DiskBackend) Get(key string) (Entry, bool) {
+	b.mu.RLock()
+	meta, found := b.meta[key]
+	b.mu.RUnlock()
+	if !found {
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, false
+	}
+
+	entry := Entry{Headers: meta.Headers, ExpiresAt: meta.ExpiresAt}
+	if entry.Expired() {
+		b.remove(key, EvictReasonTTL)
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, false
+	}
+
+	blob, err := b.openBlob(key)
+	if err != nil {
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, false
+	}
+	defer blob.Close()
+
+	body := make([]byte, blob.Size())
+	if _, err := io.ReadFull(io.NewSectionReader(blob, 0, blob.Size()), body); err != nil {
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, false
+	}
+	entry.Body = body
+
+	atomic.AddUint64(&b.hits, 1)
+	return entry, true
+}
+
+// GetBlob retrieves key as a Blob instead of a buffered Entry.Body, so a
+// caller can stream a large cached response straight to a client without
+// ever holding the whole thing in memory. It implements the BlobBackend
+// interface.
+func (b *DiskBackend) GetBlob(key string) (Entry, Blob, bool) {
+	b.mu.RLock()
+	meta, found := b.meta[key]
+	b.mu.RUnlock()
+	if !found {
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, nil, false
+	}
+
+	entry := Entry{Headers: meta.Headers, ExpiresAt: meta.ExpiresAt}
+	if entry.Expired() {
+		b.remove(key, EvictReasonTTL)
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, nil, false
+	}
+
+	blob, err := b.openBlob(key)
+	if err != nil {
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, nil, false
+	}
+
+	atomic.AddUint64(&b.hits, 1)
+	return entry, blob, true
+}
+
+// openBlob opens the file backing key without reading it, for callers
+// (Get, GetBlob) that then decide whether to buffer or stream it.
+func (b *DiskBackend) openBlob(key string) (Blob, error) {
+	f, err := os.Open(b.pathFor(key))
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &fileBlob{f: f, size: info.Size()}, nil
+}
+
+func (b *DiskBackend) Delete(key string) {
+	b.remove(key, EvictReasonDelete)
+}
+
+// remove drops key's metadata, file, and stream, reporting reason to
+// OnEvict if key was cached.
+func (b *DiskBackend) remove(key string, reason EvictReason) {
+	b.mu.Lock()
+	if _, found := b.meta[key]; found {
+		delete(b.meta, key)
+		atomic.AddUint64(&b.evictions, 1)
+		if b.opts.OnEvict != nil {
+			b.opts.OnEvict(key, reason)
+		}
+	}
+	b.mu.Unlock()
+
+	os.Remove(b.pathFor(key))
+	b.DeleteStream(key)
+}
+
+func (b *DiskBackend) Metrics() Metrics {
+	return Metrics{
+		Hits:      atomic.LoadUint64(&b.hits),
+		Misses:    atomic.LoadUint64(&b.misses),
+		Evictions: atomic.LoadUint64(&b.evictions),
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MemoryBackendOptions configures a MemoryBackend.
+type MemoryBackendOptions struct {
+	// MaxEntries caps the number of non-streaming entries kept in the
+	// cache. Zero means unlimited.
+	MaxEntries int
+	// MaxBytes caps the total size (sum of Entry.Body lengths) kept in the
+	// cache. Zero means unlimited.
+	MaxBytes int64
+	// DefaultTTL is applied to entries set without an explicit ExpiresAt.
+	// Zero means entries never expire.
+	DefaultTTL time.Duration
+	// OnEvict, if set, is called whenever an entry is removed.
+	OnEvict EvictFunc
+}
+
+type memoryNode struct {
+	key   string
+	entry Entry
+	size  int64
+}
+
+// MemoryBackend is an in-memory LRU Backend with optional size caps and
+// per-entry TTL. It replaces the old unbounded map-based ResponseCache.
+type MemoryBackend struct {
+	streamStore
+
+	mu      sync.Mutex
+	opts    MemoryBackendOptions
+	ll      *list.List
+	index   map[string]*list.Element
+	curSize int64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// NewMemoryBackend creates a new MemoryBackend.
+func NewMemoryBackend(opts MemoryBackendOptions) *MemoryBackend {
+	return &MemoryBackend{
+		streamStore: newStreamStore(opts.DefaultTTL, opts.MaxEntries),
+		opts:        opts,
+		ll:          list.New(),
+		index:       make(map[string]*list.Element),
+	}
+}
+
+func (b *MemoryBackend) Get(key string) (Entry, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	el, found := b.index[key]
+	if !found {
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, false
+	}
+	node := el.Value.(*memoryNode)
+	if node.entry.Expired() {
+		b.removeElement(el, EvictReasonTTL)
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, false
+	}
+
+	b.ll.MoveToFront(el)
+	atomic.AddUint64(&b.hits, 1)
+	return node.entry, true
+}
+
+func (b *MemoryBackend) Set(key string, entry Entry) error {
+	if entry.ExpiresAt.IsZero() && b.opts.DefaultTTL > 0 {
+		entry.ExpiresAt = time.Now().Add(b.opts.DefaultTTL)
+	}
+	size := int64(len(entry.Body))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if el, found := b.index[key]; found {
+		b.curSize -= el.Value.(*memoryNode).size
+		el.Value = &memoryNode{key: key, entry: entry, size: size}
+		b.curSize += size
+		b.ll.MoveToFront(el)
+	} else {
+		el := b.ll.PushFront(&memoryNode{key: key, entry: entry, size: size})
+		b.index[key] = el
+		b.curSize += size
+	}
+
+	b.evict()
+	return nil
+}
+
+// evict drops least-recently-used entries until the backend is back under
+// its configured caps. Caller must hold b.mu.
+func (b *MemoryBackend) evict() {
+	for {
+		overEntries := b.opts.MaxEntries > 0 && len(b.index) > b.opts.MaxEntries
+		overBytes := b.opts.MaxBytes > 0 && b.curSize > b.opts.MaxBytes
+		if !overEntries && !overBytes {
+			return
+		}
+		oldest := b.ll.Back()
+		if oldest == nil {
+			return
+		}
+		b.removeElement(oldest, EvictReasonCapacity)
+	}
+}
+
+// removeElement removes el from the cache. Caller must hold b.mu.
+func (b *MemoryBackend) removeElement(el *list.Element, reason EvictReason) {
+	node := el.Value.(*memoryNode)
+	b.ll.Remove(el)
+	delete(b.index, node.key)
+	b.curSize -= node.size
+	atomic.AddUint64(&b.evictions, 1)
+	if b.opts.OnEvict != nil {
+		b.opts.OnEvict(node.key, reason)
+	}
+}
+
+func (b *MemoryBackend) Delete(key string) {
+	b.mu.Lock()
+	if el, found := b.index[key]; found {
+		b.removeElement(el, EvictReasonDelete)
+	}
+	b.mu.Unlock()
+	b.DeleteStream(key)
+}
+
+func (b *MemoryBackend) Metrics() Metrics {
+	return Metrics{
+		Hits:      atomic.LoadUint64(&b.hits),
+		Misses:    atomic.LoadUint64(&b.misses),
+		Evictions: atomic.LoadUint64(&b.evictions),
+	}
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// RedisBackendOptions configures a RedisBackend.
+type RedisBackendOptions struct {
+	// Addr is the Redis server address, e.g. "localhost:6379".
+	Addr     string
+	Password string
+	DB       int
+	// KeyPrefix namespaces cache keys so a shared Redis instance can be
+	// used by more than one proxy.
+	KeyPrefix string
+	// DefaultTTL is applied to entries set without an explicit ExpiresAt.
+	DefaultTTL time.Duration
+}
+
+type redisEntry struct {
+	Body      []byte
+	Headers   http.Header
+	ExpiresAt time.Time
+}
+
+// RedisBackend stores non-streaming responses in Redis so that a fleet of
+// proxy replicas can share one cache. Streaming responses are kept
+// in-process, since a live *StreamResponse can't be shared across
+// processes.
+type RedisBackend struct {
+	streamStore
+
+	client *redis.Client
+	opts   RedisBackendOptions
+
+	hits   uint64
+	misses uint64
+}
+
+// NewRedisBackend creates a RedisBackend connected to opts.Addr.
+func NewRedisBackend(opts RedisBackendOptions) *RedisBackend {
+	return &RedisBackend{
+		streamStore: newStreamStore(opts.DefaultTTL, 0),
+		opts:        opts,
+		client: redis.NewClient(&redis.Options{
+			Addr:     opts.Addr,
+			Password: opts.Password,
+			DB:       opts.DB,
+		}),
+	}
+}
+
+func (b *RedisBackend) key(key string) string {
+	return b.opts.KeyPrefix + key
+}
+
+func (b *RedisBackend) Set(key string, entry Entry) error {
+	if entry.ExpiresAt.IsZero() && b.opts.DefaultTTL > 0 {
+		entry.ExpiresAt = time.Now().Add(b.opts.DefaultTTL)
+	}
+
+	payload, err := json.Marshal(redisEntry{Body: entry.Body, Headers: entry.Headers, ExpiresAt: entry.ExpiresAt})
+	if err != nil {
+		return err
+	}
+
+	var ttl time.Duration
+	if !entry.ExpiresAt.IsZero() {
+		ttl = time.Until(entry.ExpiresAt)
+	}
+	return b.client.Set(context.Background(), b.key(key), payload, ttl).Err()
+}
+
+func (b *RedisBackend) Get(key string) (Entry, bool) {
+	payload, err := b.client.Get(context.Background(), b.key(key)).Bytes()
+	if err != nil {
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, false
+	}
+
+	var re redisEntry
+	if err := json.Unmarshal(payload, &re); err != nil {
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, false
+	}
+
+	entry := Entry{Body: re.Body, Headers: re.Headers, ExpiresAt: re.ExpiresAt}
+	if entry.Expired() {
+		b.Delete(key)
+		atomic.AddUint64(&b.misses, 1)
+		return Entry{}, false
+	}
+
+	atomic.AddUint64(&b.hits, 1)
+	return entry, true
+}
+
+func (b *RedisBackend) Delete(key string) {
+	b.client.Del(context.Background(), b.key(key))
+	b.DeleteStream(key)
+}
+
+// Metrics reports hits and misses. Evictions is always zero: keys that
+// Redis expires are not observed, and Delete is not counted.
+func (b *RedisBackend) Metrics() Metrics {
+	return Metrics{
+		Hits:   atomic.LoadUint64(&b.hits),
+		Misses: atomic.LoadUint64(&b.misses),
+	}
+}
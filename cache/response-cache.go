@@ -1,59 +1,63 @@
 package cache
 
-import (
-	"net/http"
-	"sync"
-)
-
-type cacheEntry struct {
-	Body    []byte
-	Headers http.Header
-}
+import "net/http"
 
+// ResponseCache fronts a pluggable storage Backend (in-memory LRU, disk,
+// Redis, ...) so the rest of the proxy doesn't need to know how or where
+// responses are kept.
 type ResponseCache struct {
-	mu         sync.RWMutex
-	nonStreams map[string]cacheEntry
-	streams    map[string]*StreamResponse
+	backend Backend
 }
 
-func NewResponseCache() *ResponseCache {
-	return &ResponseCache{
-		nonStreams: make(map[string]cacheEntry),
-		streams:    make(map[string]*StreamResponse),
-	}
+// NewResponseCache wraps backend in a ResponseCache. Use NewMemoryBackend
+// for the previous unbounded-map behavior, NewDiskBackend to keep response
+// bodies on disk rather than in memory, or NewRedisBackend for persistence
+// across restarts and sharing across replicas.
+func NewResponseCache(backend Backend) *ResponseCache {
+	return &ResponseCache{backend: backend}
 }
 
 // Set stores a non-streaming response in the cache.
-func (c *ResponseCache) Set(key string, value []byte, headers http.Header) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.nonStreams[key] = cacheEntry{Body: value, Headers: headers}
+func (c *ResponseCache) Set(key string, entry Entry) error {
+	return c.backend.Set(key, entry)
 }
 
-// SetStream initializes a streaming response in the cache.
-func (c *ResponseCache) SetStream(key string, bufferSize int) *StreamResponse {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	stream := NewStreamResponse(bufferSize)
-	c.streams[key] = stream
-	return stream
+// Get retrieves a non-streaming response from the cache.
+func (c *ResponseCache) Get(key string) (Entry, bool) {
+	return c.backend.Get(key)
 }
 
-// Get retrieves a non-streaming response from the cache.
-func (c *ResponseCache) Get(key string) (cacheEntry, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	entry, exists := c.nonStreams[key]
-	if exists {
-		return entry, true
+// GetBlob retrieves a non-streaming response as a Blob instead of a
+// buffered Entry.Body, for backends (like DiskBackend) that support
+// streaming large bodies straight to a client. ok is false if the
+// underlying backend doesn't implement BlobBackend, or key isn't cached.
+func (c *ResponseCache) GetBlob(key string) (entry Entry, blob Blob, ok bool) {
+	blobBackend, supported := c.backend.(BlobBackend)
+	if !supported {
+		return Entry{}, nil, false
 	}
-	return cacheEntry{}, false
+	return blobBackend.GetBlob(key)
+}
+
+// SetStream initializes a streaming response in the cache, recording
+// headers (notably Content-Type) alongside the chunks so a later replay
+// can restore them.
+func (c *ResponseCache) SetStream(key string, bufferSize int, headers http.Header) *StreamResponse {
+	return c.backend.SetStream(key, bufferSize, headers)
 }
 
 // GetStream retrieves a streaming response from the cache.
 func (c *ResponseCache) GetStream(key string) (*StreamResponse, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	stream, exists := c.streams[key]
-	return stream, exists
+	return c.backend.GetStream(key)
+}
+
+// Delete removes key from the cache, if present.
+func (c *ResponseCache) Delete(key string) {
+	c.backend.Delete(key)
+}
+
+// Metrics reports cumulative hit/miss/eviction counts for the underlying
+// backend.
+func (c *ResponseCache) Metrics() Metrics {
+	return c.backend.Metrics()
 }
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"math"
+	"sync"
+)
+
+// DistanceMetric scores the similarity between two embedding vectors;
+// higher means more similar.
+type DistanceMetric func(a, b []float32) float64
+
+// CosineSimilarity is the default DistanceMetric.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+type semanticEntry struct {
+	embedding []float32
+	cacheKey  string
+}
+
+// SemanticCacheOptions configures a SemanticCache.
+type SemanticCacheOptions struct {
+	// Metric scores similarity between two embeddings. Defaults to
+	// CosineSimilarity.
+	Metric DistanceMetric
+	// Threshold is the minimum score a Lookup must meet to count as a hit.
+	// Defaults to 0.97.
+	Threshold float64
+	// MaxEntries caps how many embeddings are kept per scope before the
+	// oldest are evicted. Zero means unlimited.
+	MaxEntries int
+}
+
+// SemanticCache maps embedding vectors to cache keys, scoped by
+// (model, path, auth-hash), so that prompts that are worded differently
+// but mean the same thing can still hit the cache. It does a brute-force
+// cosine scan over a slice, which is fine for the small N a single proxy
+// instance sees; swap Metric/storage for an HNSW index if that stops
+// being true.
+type SemanticCache struct {
+	mu      sync.RWMutex
+	opts    SemanticCacheOptions
+	entries map[string][]semanticEntry
+}
+
+// NewSemanticCache creates a SemanticCache.
+func NewSemanticCache(opts SemanticCacheOptions) *SemanticCache {
+	if opts.Metric == nil {
+		opts.Metric = CosineSimilarity
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = 0.97
+	}
+	return &SemanticCache{
+		opts:    opts,
+		entries: make(map[string][]semanticEntry),
+	}
+}
+
+// Lookup returns the cache key of the closest embedding within scope that
+// meets the similarity threshold, along with the score it matched at.
+func (s *SemanticCache) Lookup(scope string, embedding []float32) (cacheKey string, score float64, found bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var bestKey string
+	var bestScore float64
+	for _, e := range s.entries[scope] {
+		if sc := s.opts.Metric(e.embedding, embedding); sc > bestScore {
+			bestScore = sc
+			bestKey = e.cacheKey
+		}
+	}
+
+	if bestScore >= s.opts.Threshold {
+		return bestKey, bestScore, true
+	}
+	return "", 0, false
+}
+
+// Insert records embedding -> cacheKey within scope.
+func (s *SemanticCache) Insert(scope string, embedding []float32, cacheKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := append(s.entries[scope], semanticEntry{embedding: embedding, cacheKey: cacheKey})
+	if s.opts.MaxEntries > 0 && len(entries) > s.opts.MaxEntries {
+		entries = entries[len(entries)-s.opts.MaxEntries:]
+	}
+	s.entries[scope] = entries
+}
@@ -1,41 +1,112 @@
 package cache
 
-import "sync"
+import (
+	"net/http"
+	"sync"
+	"time"
+)
 
+// recordedChunk is one chunk of an SSE body along with how long after the
+// previous chunk it arrived, so a replay can reproduce realistic pacing
+// instead of dumping everything at once.
+type recordedChunk struct {
+	Data  []byte
+	Delay time.Duration
+}
+
+// StreamResponse records a streaming (SSE) response as it arrives from the
+// upstream and lets any number of readers subscribe to it, whether it's
+// still in flight or has already finished. Writers never block: chunks are
+// appended to a growable slice under a mutex rather than sent over a
+// fixed-size channel, so a slow or absent reader can't stall the upstream
+// copy.
 type StreamResponse struct {
-	dataChan chan []byte
-	closed   bool
-	sync.Mutex
+	// Headers are the upstream response headers (notably Content-Type)
+	// captured alongside the chunks, so a replay can restore them on the
+	// client response the same way a non-streaming Entry's Headers do.
+	Headers http.Header
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	chunks []recordedChunk
+	closed bool
+	lastAt time.Time
 }
 
-func NewStreamResponse(bufferSize int) *StreamResponse {
-	return &StreamResponse{
-		dataChan: make(chan []byte, bufferSize),
-		closed:   false,
+// NewStreamResponse creates a StreamResponse. bufferSize is used as the
+// initial capacity hint for the chunk slice, and headers are the upstream
+// response headers to replay alongside the recorded chunks.
+func NewStreamResponse(bufferSize int, headers http.Header) *StreamResponse {
+	sr := &StreamResponse{
+		Headers: headers,
+		chunks:  make([]recordedChunk, 0, bufferSize),
 	}
+	sr.cond = sync.NewCond(&sr.mu)
+	return sr
 }
 
+// WriteChunk appends a chunk to the recording. It never blocks on readers.
 func (sr *StreamResponse) WriteChunk(chunk []byte) {
-	sr.Lock()
-	defer sr.Unlock()
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
 
 	if sr.closed {
 		return
 	}
 
-	sr.dataChan <- chunk
+	var delay time.Duration
+	now := time.Now()
+	if !sr.lastAt.IsZero() {
+		delay = now.Sub(sr.lastAt)
+	}
+	sr.lastAt = now
+
+	sr.chunks = append(sr.chunks, recordedChunk{Data: chunk, Delay: delay})
+	sr.cond.Broadcast()
 }
 
+// Close marks the recording complete. Subscribers that have caught up to
+// the last chunk will see their channel closed.
 func (sr *StreamResponse) Close() {
-	sr.Lock()
-	defer sr.Unlock()
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
 
 	if !sr.closed {
-		close(sr.dataChan)
 		sr.closed = true
+		sr.cond.Broadcast()
 	}
 }
 
+// ReadChunks returns a channel that replays every recorded chunk, paced by
+// the delay it originally arrived with, then closes once the stream is
+// done. Each call gets its own independent channel, so multiple clients
+// can subscribe to the same in-flight or completed stream at once.
 func (sr *StreamResponse) ReadChunks() <-chan []byte {
-	return sr.dataChan
+	out := make(chan []byte)
+
+	go func() {
+		defer close(out)
+
+		idx := 0
+		for {
+			sr.mu.Lock()
+			for idx >= len(sr.chunks) && !sr.closed {
+				sr.cond.Wait()
+			}
+			if idx >= len(sr.chunks) {
+				sr.mu.Unlock()
+				return
+			}
+			next := sr.chunks[idx]
+			idx++
+			sr.mu.Unlock()
+
+			if next.Delay > 0 {
+				time.Sleep(next.Delay)
+			}
+			out <- next.Data
+		}
+	}()
+
+	return out
 }
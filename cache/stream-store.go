@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type streamEntry struct {
+	key       string
+	stream    *StreamResponse
+	expiresAt time.Time
+}
+
+// streamStore tracks in-flight and completed streaming responses, applying
+// the same TTL and entry-count caps as the non-streaming backends so a
+// long-lived proxy's stream map doesn't grow unbounded. It is embedded by
+// backends so each one doesn't have to reimplement the bookkeeping around
+// *StreamResponse.
+//
+// SetStream, GetStream, and DeleteStream are exported (rather than
+// lowercase promoted methods) so that embedding streamStore is enough for
+// MemoryBackend, DiskBackend, and RedisBackend to satisfy Backend's
+// SetStream/GetStream contract.
+type streamStore struct {
+	mu         sync.Mutex
+	streams    map[string]*list.Element
+	ll         *list.List
+	ttl        time.Duration
+	maxEntries int
+}
+
+// newStreamStore creates a streamStore. ttl (zero means never expire) and
+// maxEntries (zero means unlimited) mirror the backend's own DefaultTTL
+// and MaxEntries options, so streaming and non-streaming entries are
+// bounded the same way.
+func newStreamStore(ttl time.Duration, maxEntries int) streamStore {
+	return streamStore{
+		streams:    make(map[string]*list.Element),
+		ll:         list.New(),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+	}
+}
+
+func (s *streamStore) SetStream(key string, bufferSize int, headers http.Header) *StreamResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream := NewStreamResponse(bufferSize, headers)
+	var expiresAt time.Time
+	if s.ttl > 0 {
+		expiresAt = time.Now().Add(s.ttl)
+	}
+
+	if el, found := s.streams[key]; found {
+		s.ll.Remove(el)
+	}
+	el := s.ll.PushFront(&streamEntry{key: key, stream: stream, expiresAt: expiresAt})
+	s.streams[key] = el
+
+	s.evict()
+	return stream
+}
+
+func (s *streamStore) GetStream(key string) (*StreamResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, found := s.streams[key]
+	if !found {
+		return nil, false
+	}
+	entry := el.Value.(*streamEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		s.removeElement(el)
+		return nil, false
+	}
+	return entry.stream, true
+}
+
+func (s *streamStore) DeleteStream(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, found := s.streams[key]; found {
+		s.removeElement(el)
+	}
+}
+
+// evict drops the oldest stream entries once maxEntries is exceeded.
+// Caller must hold s.mu.
+func (s *streamStore) evict() {
+	for s.maxEntries > 0 && len(s.streams) > s.maxEntries {
+		oldest := s.ll.Back()
+		if oldest == nil {
+			return
+		}
+		s.removeElement(oldest)
+	}
+}
+
+// removeElement removes el from the store. Caller must hold s.mu.
+func (s *streamStore) removeElement(el *list.Element) {
+	entry := el.Value.(*streamEntry)
+	s.ll.Remove(el)
+	delete(s.streams, entry.key)
+}
@@ -6,21 +6,52 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/andybalholm/brotli"
+	redis "github.com/redis/go-redis/v9"
+
+	"github.com/jsfour/model-proxy/audit"
 	cache "github.com/jsfour/model-proxy/cache"
 	provider "github.com/jsfour/model-proxy/providers"
+	ratelimit "github.com/jsfour/model-proxy/ratelimit"
 )
 
+// streamCacheBufferSize is the initial chunk-slice capacity handed to
+// cache.NewStreamResponse for each captured SSE stream.
+const streamCacheBufferSize = 64
+
+// sseTransport wraps the proxy's RoundTripper so that streaming responses
+// are captured into the response cache as they're read, in addition to
+// being passed straight through to the caller.
 type sseTransport struct {
 	Transport http.RoundTripper
+	Cache     *cache.ResponseCache
+	CacheKey  string
+	// Capture, if non-nil, also receives every (translated) chunk read
+	// from the upstream body, so an audit record can report the full completion
+	// for a streaming response without buffering it separately.
+	Capture *completionCapture
+	// Streaming reports whether the client asked for stream:true, so
+	// RoundTrip knows to tee the body into the stream cache even for a
+	// provider (Cohere, Ollama) whose native streaming format isn't
+	// served under a text/event-stream Content-Type.
+	Streaming bool
+	// Translator, if non-nil, rewrites the provider's native streaming
+	// format into OpenAI SSE chunk-by-chunk as the body is read, instead
+	// of passing it through verbatim.
+	Translator provider.StreamChunkTranslator
 }
 
 func (t *sseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -30,18 +61,24 @@ func (t *sseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 
-	// Check if this is an event stream.
-	if req.Header.Get("Accept") == "text/event-stream" {
-		// Make a flushing response writer if needed.
-		flusher, ok := resp.Body.(http.Flusher)
-		if !ok {
-			return nil, io.EOF
+	if t.Streaming {
+		if t.Translator != nil {
+			// The translated body is always OpenAI SSE, regardless of
+			// what Content-Type the provider served its native stream
+			// under (e.g. Cohere/Ollama's newline-delimited JSON).
+			resp.Header.Set("Content-Type", "text/event-stream")
 		}
 
-		// Now wrap the response body in our streaming reader.
+		stream := t.Cache.SetStream(t.CacheKey, streamCacheBufferSize, resp.Header.Clone())
+
+		// Tee every (translated) chunk read from the upstream body into
+		// the stream cache, and close it once the upstream body is
+		// exhausted.
 		resp.Body = &sseReader{
-			reader:  resp.Body,
-			flusher: flusher,
+			reader:     resp.Body,
+			stream:     stream,
+			capture:    t.Capture,
+			translator: t.Translator,
 		}
 	}
 
@@ -49,23 +86,84 @@ func (t *sseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+// completionCapture accumulates the bytes of a streaming response, after
+// any stream translation, as they flow through sseReader, so the audit log can report the completion
+// for a streaming request the same way it does for a non-streaming one.
+type completionCapture struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newCompletionCapture() *completionCapture {
+	return &completionCapture{}
+}
+
+func (c *completionCapture) write(p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buf.Write(p)
+}
+
+func (c *completionCapture) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.String()
+}
+
+func isEventStream(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/event-stream")
+}
+
+// sseReader tees bytes read from an upstream streaming body into a
+// cache.StreamResponse so that repeated identical streaming requests can
+// be served from cache instead of hitting the upstream again. If
+// translator is non-nil, each chunk is rewritten from the provider's
+// native streaming format into OpenAI SSE before being teed and returned,
+// instead of being passed through verbatim.
 type sseReader struct {
-	reader  io.ReadCloser
-	flusher http.Flusher
-	buffer  bytes.Buffer
+	reader     io.ReadCloser
+	stream     *cache.StreamResponse
+	capture    *completionCapture
+	translator provider.StreamChunkTranslator
+
+	// pending holds translated bytes not yet copied out to a caller,
+	// since a translated chunk can be larger than the caller's buffer or
+	// (while a partial line is buffered) smaller than the chunk read from
+	// upstream. readErr is the error from the upstream read that produced
+	// pending, surfaced only once pending is fully drained.
+	pending []byte
+	readErr error
 }
 
-func (r *sseReader) Read(p []byte) (n int, err error) {
-	// Read data from the original stream.
-	n, err = r.reader.Read(p)
+func (r *sseReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 && r.readErr == nil {
+		buf := make([]byte, 32*1024)
+		n, err := r.reader.Read(buf)
 
-	// If this was a successful read, flush the data.
-	if n > 0 {
-		if flusher, ok := r.flusher.(http.Flusher); ok {
-			flusher.Flush()
+		if n > 0 {
+			chunk := buf[:n]
+			if r.translator != nil {
+				chunk = r.translator.Translate(chunk)
+			}
+			if len(chunk) > 0 {
+				r.stream.WriteChunk(chunk)
+				if r.capture != nil {
+					r.capture.write(chunk)
+				}
+				r.pending = chunk
+			}
 		}
+		r.readErr = err
 	}
-	return n, err
+
+	if len(r.pending) == 0 {
+		r.stream.Close()
+		return 0, r.readErr
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
 }
 
 func (r *sseReader) Close() error {
@@ -82,13 +180,15 @@ func NewServiceResolver() *ServiceResolver {
 		providers: make(map[string]provider.IModelProvider),
 	}
 
-	openai := provider.NewOpenAIProvider()
-
-	svc.Register(openai)
+	svc.Register(provider.NewOpenAIProvider())
+	svc.Register(provider.NewAnthropicProvider())
+	svc.Register(provider.NewGeminiProvider())
+	svc.Register(provider.NewCohereProvider())
+	svc.Register(provider.NewOllamaProvider())
 	return svc
 }
 
-func (r *ServiceResolver) GetReverseProxy(req *http.Request) (*httputil.ReverseProxy, error) {
+func (r *ServiceResolver) GetReverseProxy(req *http.Request, respCache *cache.ResponseCache, cacheKey string, capture *completionCapture) (*httputil.ReverseProxy, string, error) {
 	var requestBody struct {
 		Model  string `json:"model,omitempty"`
 		Stream bool   `json:"stream,omitempty"`
@@ -97,19 +197,19 @@ func (r *ServiceResolver) GetReverseProxy(req *http.Request) (*httputil.ReverseP
 	tee := io.TeeReader(req.Body, &requestBodyBuffer)
 	err := json.NewDecoder(tee).Decode(&requestBody)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	req.Body.Close()
 
 	req.Body = io.NopCloser(&requestBodyBuffer)
 
 	if requestBody.Model == "" {
-		return nil, errors.New("Model not specified in the request")
+		return nil, "", errors.New("Model not specified in the request")
 	}
 
 	provider, found := r.Resolve(requestBody.Model)
 	if !found {
-		return nil, errors.New("Service not found for the specified model")
+		return nil, "", errors.New("Service not found for the specified model")
 	}
 
 	targetURL := provider.GetEndpoint()
@@ -117,7 +217,7 @@ func (r *ServiceResolver) GetReverseProxy(req *http.Request) (*httputil.ReverseP
 	log.Println("Targeting model", requestBody.Model, "at", targetURL)
 	target, err := url.Parse(targetURL)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	proxy := httputil.NewSingleHostReverseProxy(target)
@@ -127,31 +227,84 @@ func (r *ServiceResolver) GetReverseProxy(req *http.Request) (*httputil.ReverseP
 		req.Host = target.Host
 		req.URL.Scheme = target.Scheme
 		req.URL.Host = target.Host
+
+		// Count tokens against the OpenAI-format body clients actually
+		// sent, before TranslateRequest rewrites it into the provider's
+		// native schema.
 		tokensCount, err := provider.CountTokens(req, requestBody.Model)
 		if err != nil {
 			log.Println("Error counting tokens:", err)
 		}
 		log.Println("Proxying request to", req.URL.String(), "with", tokensCount, "tokens")
+
+		if err := provider.TranslateRequest(req); err != nil {
+			log.Println("Error translating request for provider:", err)
+		}
+	}
+
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		// Streaming bodies are rewritten (if the provider needs it) and
+		// teed into the cache by sseTransport as they're read, not here.
+		if requestBody.Stream {
+			return nil
+		}
+		return provider.TranslateResponse(resp)
 	}
 
-	// if requestBody.Stream {
 	originalTransport := proxy.Transport
 	if originalTransport == nil {
 		originalTransport = http.DefaultTransport
 	}
+
+	translator := streamTranslatorFor(provider, requestBody.Model, requestBody.Stream)
+
 	proxy.Transport = &sseTransport{
-		Transport: originalTransport,
+		Transport:  originalTransport,
+		Cache:      respCache,
+		CacheKey:   cacheKey,
+		Capture:    capture,
+		Streaming:  requestBody.Stream,
+		Translator: translator,
 	}
-	// }
 
-	return proxy, nil
+	return proxy, requestBody.Model, nil
+}
+
+// streamTranslatorFor returns p's stream translator for model, or nil if
+// streaming wasn't requested or p doesn't need one (its native streaming
+// format is already OpenAI SSE, as for OpenAI itself). It's a free
+// function rather than inlined where it's used because the local variable
+// named "provider" there shadows the package import of the same name.
+func streamTranslatorFor(p provider.IModelProvider, model string, streaming bool) provider.StreamChunkTranslator {
+	if !streaming {
+		return nil
+	}
+	if translator, ok := p.(provider.StreamTranslator); ok {
+		return translator.NewStreamTranslator(model)
+	}
+	return nil
 }
 
 func (r *ServiceResolver) Resolve(modelName string) (provider.IModelProvider, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	provider, exists := r.providers[modelName]
-	return provider, exists
+
+	if provider, exists := r.providers[modelName]; exists {
+		return provider, true
+	}
+
+	// Providers like Anthropic, Gemini, Cohere, and Ollama register a
+	// model-name prefix ("claude-", "gemini-", "command-", "ollama/")
+	// rather than an exhaustive model list, so fall back to a prefix scan.
+	for registeredName, provider := range r.providers {
+		if strings.HasSuffix(registeredName, "-") || strings.HasSuffix(registeredName, "/") {
+			if strings.HasPrefix(modelName, registeredName) {
+				return provider, true
+			}
+		}
+	}
+
+	return nil, false
 }
 
 func (r *ServiceResolver) Register(provider provider.IModelProvider) {
@@ -163,28 +316,338 @@ func (r *ServiceResolver) Register(provider provider.IModelProvider) {
 	}
 }
 
+// newCacheBackend picks the cache backend to use based on environment
+// configuration. It defaults to an in-memory LRU so the proxy keeps
+// working out of the box with no config at all.
+func newCacheBackend() cache.Backend {
+	var ttl time.Duration
+	if raw := os.Getenv("MODEL_PROXY_CACHE_TTL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil {
+			ttl = parsed
+		} else {
+			log.Println("Invalid MODEL_PROXY_CACHE_TTL, ignoring:", err)
+		}
+	}
+
+	switch os.Getenv("MODEL_PROXY_CACHE_BACKEND") {
+	case "disk":
+		dir := os.Getenv("MODEL_PROXY_CACHE_DIR")
+		if dir == "" {
+			dir = "cache-data"
+		}
+		backend, err := cache.NewDiskBackend(cache.DiskBackendOptions{Dir: dir, DefaultTTL: ttl})
+		if err != nil {
+			log.Fatal("Failed to initialize disk cache backend:", err)
+		}
+		return backend
+	case "redis":
+		db, _ := strconv.Atoi(os.Getenv("MODEL_PROXY_REDIS_DB"))
+		return cache.NewRedisBackend(cache.RedisBackendOptions{
+			Addr:       os.Getenv("MODEL_PROXY_REDIS_ADDR"),
+			Password:   os.Getenv("MODEL_PROXY_REDIS_PASSWORD"),
+			DB:         db,
+			KeyPrefix:  "model-proxy:",
+			DefaultTTL: ttl,
+		})
+	default:
+		maxEntries, _ := strconv.Atoi(os.Getenv("MODEL_PROXY_CACHE_MAX_ENTRIES"))
+		maxBytes, _ := strconv.ParseInt(os.Getenv("MODEL_PROXY_CACHE_MAX_BYTES"), 10, 64)
+		return cache.NewMemoryBackend(cache.MemoryBackendOptions{
+			MaxEntries: maxEntries,
+			MaxBytes:   maxBytes,
+			DefaultTTL: ttl,
+		})
+	}
+}
+
+// newRateLimiter builds a Limiter from environment configuration. Rate
+// limiting is disabled (newRateLimiter returns nil) unless at least one of
+// the three budgets is configured.
+func newRateLimiter() *ratelimit.Limiter {
+	config := ratelimit.Config{
+		RequestsPerMinute:  rateLimitFromEnv("MODEL_PROXY_RATE_LIMIT_RPM", time.Minute),
+		PromptTokensPerMin: rateLimitFromEnv("MODEL_PROXY_RATE_LIMIT_PROMPT_TPM", time.Minute),
+		TotalTokensPerDay:  rateLimitFromEnv("MODEL_PROXY_RATE_LIMIT_TOTAL_TPD", 24*time.Hour),
+	}
+	if config.RequestsPerMinute.Capacity == 0 && config.PromptTokensPerMin.Capacity == 0 && config.TotalTokensPerDay.Capacity == 0 {
+		return nil
+	}
+
+	var store ratelimit.Store
+	if addr := os.Getenv("MODEL_PROXY_RATE_LIMIT_REDIS_ADDR"); addr != "" {
+		db, _ := strconv.Atoi(os.Getenv("MODEL_PROXY_REDIS_DB"))
+		client := redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: os.Getenv("MODEL_PROXY_REDIS_PASSWORD"),
+			DB:       db,
+		})
+		store = ratelimit.NewRedisStore(client, "model-proxy:ratelimit:")
+	} else {
+		store = ratelimit.NewMemoryStore()
+	}
+
+	return ratelimit.NewLimiter(store, config)
+}
+
+// rateLimitFromEnv parses a budget's capacity from an environment variable
+// like MODEL_PROXY_RATE_LIMIT_RPM, refilling over window. A missing or
+// unparsable value disables that budget (zero Capacity).
+func rateLimitFromEnv(envVar string, window time.Duration) ratelimit.Limit {
+	raw := os.Getenv(envVar)
+	if raw == "" {
+		return ratelimit.Limit{}
+	}
+	capacity, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.Println("Invalid", envVar, "ignoring:", err)
+		return ratelimit.Limit{}
+	}
+	return ratelimit.Limit{Capacity: capacity, Window: window}
+}
+
+// rateLimitKeyFor scopes a rate-limit bucket to the caller's bearer token
+// and the model they're calling, so one noisy model can't exhaust another's
+// budget.
+func rateLimitKeyFor(r *http.Request, model string) string {
+	hash := sha256.Sum256([]byte(r.Header.Get("Authorization")))
+	return hex.EncodeToString(hash[:]) + "|" + model
+}
+
+// writeRateLimitError responds with a 429 and an OpenAI-shaped error body
+// when a *ratelimit.LimitExceededError trips one of the caller's budgets.
+func writeRateLimitError(w http.ResponseWriter, err error) {
+	var limitErr *ratelimit.LimitExceededError
+	if errors.As(err, &limitErr) {
+		if limitErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(limitErr.RetryAfter.Seconds()+1)))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{
+			"message": err.Error(),
+			"type":    "rate_limit_error",
+			"code":    "rate_limit_exceeded",
+		},
+	})
+}
+
+// completionTokensFrom extracts the completion-token count an upstream
+// response reports in its usage object, so it can be charged against the
+// caller's rate-limit budget after the fact (prompt tokens are already
+// accounted for up front, in CheckRequest). Streaming responses report
+// usage on their final SSE event rather than in the body as a whole.
+func completionTokensFrom(contentType string, body []byte) int {
+	if isEventStream(contentType) {
+		return completionTokensFromSSE(body)
+	}
+	return completionTokensFromJSON(body)
+}
+
+func completionTokensFromJSON(body []byte) int {
+	var parsed struct {
+		Usage struct {
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return 0
+	}
+	return parsed.Usage.CompletionTokens
+}
+
+func completionTokensFromSSE(body []byte) int {
+	var lastData []byte
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		data := bytes.TrimPrefix(line, []byte("data: "))
+		if len(data) == len(line) || bytes.Equal(data, []byte("[DONE]")) {
+			continue
+		}
+		lastData = data
+	}
+	if lastData == nil {
+		return 0
+	}
+	return completionTokensFromJSON(lastData)
+}
+
+// newAuditSink builds the configured audit.Sink from environment, or nil
+// if auditing isn't enabled (the default).
+func newAuditSink() audit.Sink {
+	path := os.Getenv("MODEL_PROXY_AUDIT_PATH")
+
+	switch os.Getenv("MODEL_PROXY_AUDIT_SINK") {
+	case "jsonl":
+		if path == "" {
+			path = "audit.jsonl"
+		}
+		sink, err := audit.NewJSONLSink(path)
+		if err != nil {
+			log.Fatal("Failed to open audit log:", err)
+		}
+		return sink
+	case "sqlite":
+		if path == "" {
+			path = "audit.db"
+		}
+		sink, err := audit.NewSQLiteSink(path)
+		if err != nil {
+			log.Fatal("Failed to open audit database:", err)
+		}
+		return sink
+	case "webhook":
+		url := os.Getenv("MODEL_PROXY_AUDIT_WEBHOOK_URL")
+		if url == "" {
+			log.Fatal("MODEL_PROXY_AUDIT_WEBHOOK_URL must be set when MODEL_PROXY_AUDIT_SINK=webhook")
+		}
+		return audit.NewWebhookSink(url)
+	default:
+		return nil
+	}
+}
+
 func main() {
 	// RunLlama()
-	cache := cache.NewResponseCache()
+	respCache := cache.NewResponseCache(newCacheBackend())
+
+	var semanticCache *cache.SemanticCache
+	semanticEnabled := os.Getenv("MODEL_PROXY_SEMANTIC_CACHE") == "true"
+	if semanticEnabled {
+		semanticCache = newSemanticCache()
+	}
+	semanticEmbeddingModel := os.Getenv("MODEL_PROXY_SEMANTIC_CACHE_MODEL")
+	if semanticEmbeddingModel == "" {
+		semanticEmbeddingModel = "text-embedding-3-small"
+	}
+	// semanticEmbeddingAPIKey authenticates to OpenAI's embeddings endpoint
+	// on the proxy's own behalf. It can't reuse the caller's Authorization
+	// header, since that header is frequently a claude-*/gemini-*/command-*
+	// provider's own key and would 401 against OpenAI.
+	semanticEmbeddingAPIKey := os.Getenv("MODEL_PROXY_SEMANTIC_CACHE_API_KEY")
+	if semanticEnabled && semanticEmbeddingAPIKey == "" {
+		log.Println("MODEL_PROXY_SEMANTIC_CACHE_API_KEY is not set; semantic cache embedding calls will fail")
+	}
+
+	limiter := newRateLimiter()
+
+	auditSink := newAuditSink()
+	auditLogBodies := os.Getenv("MODEL_PROXY_AUDIT_LOG_BODIES") == "true"
 
 	resolver := NewServiceResolver()
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		proxy, err := resolver.GetReverseProxy(r)
 
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+	http.HandleFunc("/_proxy/usage", func(w http.ResponseWriter, r *http.Request) {
+		if limiter == nil {
+			http.Error(w, "Rate limiting is not enabled", http.StatusNotFound)
 			return
 		}
+		key := rateLimitKeyFor(r, r.URL.Query().Get("model"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(limiter.Usage(key))
+	})
 
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		log.Println("Request received")
-		cacheKey, err := generateCacheKey(r)
+		cacheKey, bodySHA, err := generateCacheKey(r)
 		if err != nil {
 			http.Error(w, "Failed to generate cache key", http.StatusInternalServerError)
 			return
 		}
 
-		if cacheEntry, found := cache.Get(cacheKey); found {
+		// Audit state, filled in as the request is handled and flushed by
+		// the deferred Write below regardless of which path returns.
+		var (
+			auditModel            string
+			auditPromptTokens     int
+			auditCompletionTokens int
+			auditCacheStatus      = "miss"
+			auditHTTPStatus       = http.StatusOK
+			auditUpstreamStart    time.Time
+			auditResponse         string
+		)
+		if auditSink != nil {
+			authHash := sha256.Sum256([]byte(r.Header.Get("Authorization")))
+			rec := audit.Record{Timestamp: time.Now(), AuthHash: hex.EncodeToString(authHash[:]), RequestBodySHA: bodySHA}
+			if auditLogBodies {
+				if _, text, err := extractUserMessages(r); err == nil {
+					rec.Prompt = text
+				}
+			}
+			defer func() {
+				rec.Model = auditModel
+				rec.PromptTokens = auditPromptTokens
+				rec.CompletionTokens = auditCompletionTokens
+				rec.CacheStatus = auditCacheStatus
+				rec.HTTPStatus = auditHTTPStatus
+				if !auditUpstreamStart.IsZero() {
+					rec.LatencyMS = time.Since(auditUpstreamStart).Milliseconds()
+				}
+				if auditLogBodies {
+					rec.Response = auditResponse
+				}
+				if err := auditSink.Write(rec); err != nil {
+					log.Println("Error writing audit record:", err)
+				}
+			}()
+		}
+
+		var capture *completionCapture
+		if auditSink != nil {
+			capture = newCompletionCapture()
+		}
+
+		proxy, model, err := resolver.GetReverseProxy(r, respCache, cacheKey, capture)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			auditHTTPStatus = http.StatusBadRequest
+			return
+		}
+		auditModel = model
+
+		promptTokens := 0
+		if resolvedProvider, found := resolver.Resolve(model); found {
+			if count, err := resolvedProvider.CountTokens(r, model); err != nil {
+				log.Println("Error counting tokens:", err)
+			} else {
+				promptTokens = count
+			}
+		}
+		auditPromptTokens = promptTokens
+
+		rateLimitKey := rateLimitKeyFor(r, model)
+		if limiter != nil {
+			if err := limiter.CheckRequest(rateLimitKey, promptTokens); err != nil {
+				writeRateLimitError(w, err)
+				auditHTTPStatus = http.StatusTooManyRequests
+				auditCacheStatus = "rejected"
+				return
+			}
+		}
+
+		// Prefer the blob path when the backend supports it (DiskBackend),
+		// so a large cached response streams straight to the client
+		// instead of being read fully into memory first.
+		if blobEntry, blob, found := respCache.GetBlob(cacheKey); found {
+			log.Printf("Cache hit (streamed): %d bytes", blob.Size())
+			auditCacheStatus = "hit"
+
+			w.Header().Set("model-proxy-cache", "hit")
+			for key, values := range blobEntry.Headers {
+				for _, value := range values {
+					w.Header().Add(key, value)
+				}
+			}
+
+			io.Copy(w, io.NewSectionReader(blob, 0, blob.Size()))
+			blob.Close()
+			return
+		}
+
+		if cacheEntry, found := respCache.Get(cacheKey); found {
 			log.Printf("Cache hit: %d bytes", len(cacheEntry.Body))
+			auditCacheStatus = "hit"
 
 			w.Header().Set("model-proxy-cache", "hit")
 
@@ -210,8 +673,18 @@ func main() {
 			return
 		}
 
-		if streamCache, found := cache.GetStream(cacheKey); found {
+		if streamCache, found := respCache.GetStream(cacheKey); found {
 			log.Println("Cache hit stream")
+			auditCacheStatus = "hit-stream"
+
+			for key, values := range streamCache.Headers {
+				for _, value := range values {
+					w.Header().Add(key, value)
+				}
+			}
+			if w.Header().Get("Content-Type") == "" {
+				w.Header().Set("Content-Type", "text/event-stream")
+			}
 			w.Header().Set("model-proxy-cache", "hit")
 
 			// Since we have a streaming response, we will read from the stream's channel
@@ -233,12 +706,50 @@ func main() {
 			return
 		}
 
+		// Compute the semantic embedding only once the exact-key and stream
+		// caches have both missed, so a request that would hit either of
+		// those skips the embeddings round-trip entirely.
+		var semanticEmbedding []float32
+		var semanticScopeKey string
+		if semanticEnabled && r.Header.Get("model-proxy-disable-semantic-cache") != "true" {
+			if model, text, extractErr := extractUserMessages(r); extractErr != nil {
+				log.Println("Error extracting messages for semantic cache:", extractErr)
+			} else if text != "" {
+				if embedding, embedErr := fetchEmbedding(semanticEmbeddingAPIKey, semanticEmbeddingModel, text); embedErr != nil {
+					log.Println("Error fetching embedding for semantic cache:", embedErr)
+				} else {
+					semanticEmbedding = embedding
+					semanticScopeKey = semanticScope(model, r.URL.Path, r.Header.Get("Authorization"))
+				}
+			}
+		}
+
+		if semanticEmbedding != nil {
+			if matchKey, score, found := semanticCache.Lookup(semanticScopeKey, semanticEmbedding); found {
+				if cacheEntry, ok := respCache.Get(matchKey); ok {
+					log.Printf("Semantic cache hit (similarity %.4f)", score)
+					auditCacheStatus = "semantic-hit"
+					w.Header().Set("model-proxy-cache", "semantic-hit")
+					w.Header().Set("model-proxy-semantic-score", strconv.FormatFloat(score, 'f', 4, 64))
+					for key, values := range cacheEntry.Headers {
+						for _, value := range values {
+							w.Header().Add(key, value)
+						}
+					}
+					w.Write(cacheEntry.Body)
+					return
+				}
+			}
+		}
+
 		// play back response
 		log.Println("Cache miss")
 
 		// Capture the response
+		auditUpstreamStart = time.Now()
 		rec := httptest.NewRecorder()
 		proxy.ServeHTTP(rec, r)
+		auditHTTPStatus = rec.Code
 
 		responseBody, err := io.ReadAll(rec.Body)
 		if err != nil {
@@ -252,8 +763,10 @@ func main() {
 			return
 		}
 
-		// Cache the response if the status code indicates success
-		if rec.Code >= 200 && rec.Code < 300 {
+		// Cache the response if the status code indicates success. Streamed
+		// SSE responses are cached as they're read by sseTransport instead,
+		// so they can be replayed with their original chunk pacing.
+		if rec.Code >= 200 && rec.Code < 300 && !isEventStream(rec.Header().Get("Content-Type")) {
 			log.Printf("Caching response of size %d bytes", len(responseBody))
 			// Create headers with application type and content encoding if they exist
 			headers := http.Header{}
@@ -263,7 +776,25 @@ func main() {
 			if contentEncoding := rec.Header().Get("Content-Encoding"); contentEncoding != "" {
 				headers.Set("Content-Encoding", contentEncoding)
 			}
-			cache.Set(cacheKey, responseBody, headers)
+			if err := respCache.Set(cacheKey, cache.Entry{Body: responseBody, Headers: headers}); err != nil {
+				log.Println("Error caching response:", err)
+			}
+			if semanticEmbedding != nil {
+				semanticCache.Insert(semanticScopeKey, semanticEmbedding, cacheKey)
+			}
+		}
+
+		completionTokens := completionTokensFrom(rec.Header().Get("Content-Type"), responseBody)
+		auditCompletionTokens = completionTokens
+		if auditLogBodies {
+			if isEventStream(rec.Header().Get("Content-Type")) && capture != nil {
+				auditResponse = capture.String()
+			} else {
+				auditResponse = string(responseBody)
+			}
+		}
+		if limiter != nil && rec.Code >= 200 && rec.Code < 300 {
+			limiter.ChargeCompletionTokens(rateLimitKey, completionTokens)
 		}
 
 		// Copy the captured response to the actual response
@@ -281,16 +812,20 @@ func main() {
 	log.Fatal(http.ListenAndServe(":8080", nil))
 }
 
-// generateCacheKey creates a unique string based on the request's path, body, and headers.
-func generateCacheKey(r *http.Request) (string, error) {
+// generateCacheKey creates a unique string based on the request's path, body,
+// and headers, along with the plain SHA-256 of the body alone (used for the
+// audit log, which cares about the body independent of who sent it).
+func generateCacheKey(r *http.Request) (cacheKey string, bodySHA string, err error) {
 	// Read the body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	// Restore the body so it can be read again
 	r.Body = io.NopCloser(bytes.NewReader(body))
 
+	bodyHash := sha256.Sum256(body)
+
 	// Create a hash
 	hash := sha256.New()
 	if bearerToken := r.Header.Get("Authorization"); bearerToken != "" {
@@ -298,5 +833,105 @@ func generateCacheKey(r *http.Request) (string, error) {
 	}
 	hash.Write([]byte(r.URL.Path))
 	hash.Write(body)
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return hex.EncodeToString(hash.Sum(nil)), hex.EncodeToString(bodyHash[:]), nil
+}
+
+// semanticEmbeddingEndpoint is OpenAI's embeddings endpoint, used to embed
+// prompts for the semantic cache regardless of which provider ultimately
+// serves the request.
+const semanticEmbeddingEndpoint = "https://api.openai.com/v1/embeddings"
+
+// newSemanticCache builds a SemanticCache from environment configuration.
+func newSemanticCache() *cache.SemanticCache {
+	threshold := 0.0
+	if raw := os.Getenv("MODEL_PROXY_SEMANTIC_CACHE_THRESHOLD"); raw != "" {
+		if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+			threshold = parsed
+		} else {
+			log.Println("Invalid MODEL_PROXY_SEMANTIC_CACHE_THRESHOLD, ignoring:", err)
+		}
+	}
+	return cache.NewSemanticCache(cache.SemanticCacheOptions{Threshold: threshold})
+}
+
+// extractUserMessages pulls the model name and concatenated user-message
+// content out of an OpenAI-format chat completion body, restoring the body
+// afterward so later reads (token counting, provider translation) see the
+// whole thing again.
+func extractUserMessages(r *http.Request) (model string, text string, err error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", "", err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", "", err
+	}
+
+	var userText []string
+	for _, m := range payload.Messages {
+		if m.Role == "user" {
+			userText = append(userText, m.Content)
+		}
+	}
+	return payload.Model, strings.Join(userText, "\n"), nil
+}
+
+// semanticScope keys the semantic cache by model, path, and a hash of the
+// caller's bearer token, so callers never share semantic matches across
+// models, endpoints, or accounts.
+func semanticScope(model, path, authHeader string) string {
+	hash := sha256.Sum256([]byte(authHeader))
+	return model + "|" + path + "|" + hex.EncodeToString(hash[:])
+}
+
+// fetchEmbedding calls OpenAI's embeddings endpoint to vectorize text,
+// authenticating with the proxy's own configured apiKey rather than the
+// caller's bearer token, since the caller may be authenticating to a
+// different provider entirely.
+func fetchEmbedding(apiKey, model, text string) ([]float32, error) {
+	payload, err := json.Marshal(map[string]string{"model": model, "input": text})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, semanticEmbeddingEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("embeddings request failed with status %d", resp.StatusCode)
+	}
+
+	var parsed struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+	if len(parsed.Data) == 0 {
+		return nil, errors.New("embeddings response had no data")
+	}
+	return parsed.Data[0].Embedding, nil
 }
@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// AnthropicProvider speaks Anthropic's /v1/messages schema (a top-level
+// `system` string plus a `messages` array, rather than a `system` role
+// inside `messages`).
+type AnthropicProvider struct {
+	endpoint string
+	models   []string
+}
+
+// NewAnthropicProvider creates an AnthropicProvider. models is registered
+// as a prefix match in ServiceResolver, so "claude-" routes every
+// claude-* model here.
+func NewAnthropicProvider() *AnthropicProvider {
+	return &AnthropicProvider{
+		endpoint: "https://api.anthropic.com",
+		models:   []string{"claude-"},
+	}
+}
+
+func (a *AnthropicProvider) GetEndpoint() string { return a.endpoint }
+
+func (a *AnthropicProvider) GetModels() []string { return a.models }
+
+func (a *AnthropicProvider) CountTokens(req *http.Request, model string) (int, error) {
+	content, err := parseMessagesContent(req)
+	if err != nil {
+		return 0, err
+	}
+	return approximateTokenCount(strings.Join(content, " ")), nil
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	MaxTokens int                `json:"max_tokens"`
+	Stream    bool               `json:"stream,omitempty"`
+}
+
+// TranslateRequest rewrites an OpenAI-format chat completion body into
+// Anthropic's {system, messages} shape, points the request at
+// /v1/messages, and swaps the inbound bearer token for the x-api-key
+// header Anthropic expects.
+func (a *AnthropicProvider) TranslateRequest(req *http.Request) error {
+	var payload struct {
+		Model     string             `json:"model"`
+		Messages  []anthropicMessage `json:"messages"`
+		MaxTokens int                `json:"max_tokens,omitempty"`
+		Stream    bool               `json:"stream,omitempty"`
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Errorf("decode OpenAI-format request: %w", err)
+	}
+
+	translated := anthropicRequest{
+		Model:     payload.Model,
+		MaxTokens: payload.MaxTokens,
+		Stream:    payload.Stream,
+	}
+	if translated.MaxTokens == 0 {
+		translated.MaxTokens = 4096
+	}
+
+	for _, m := range payload.Messages {
+		if m.Role == "system" {
+			translated.System = m.Content
+			continue
+		}
+		translated.Messages = append(translated.Messages, m)
+	}
+
+	out, err := json.Marshal(translated)
+	if err != nil {
+		return err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(out))
+	req.ContentLength = int64(len(out))
+	req.URL.Path = "/v1/messages"
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	// Anthropic authenticates via x-api-key, not a bearer token.
+	req.Header.Set("x-api-key", bearerToken(req))
+	req.Header.Del("Authorization")
+	return nil
+}
+
+type anthropicResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// TranslateResponse rewrites an Anthropic /v1/messages response back into
+// OpenAI's chat completion shape. Non-JSON bodies (SSE streams) are left
+// untouched here; NewStreamTranslator handles those chunk-by-chunk instead.
+func (a *AnthropicProvider) TranslateResponse(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	var parsed anthropicResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return nil
+	}
+
+	var text strings.Builder
+	for _, block := range parsed.Content {
+		text.WriteString(block.Text)
+	}
+
+	translated := map[string]interface{}{
+		"id":     parsed.ID,
+		"object": "chat.completion",
+		"model":  parsed.Model,
+		"choices": []map[string]interface{}{
+			{
+				"index": 0,
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": text.String(),
+				},
+				"finish_reason": parsed.StopReason,
+			},
+		},
+		"usage": map[string]int{
+			"prompt_tokens":     parsed.Usage.InputTokens,
+			"completion_tokens": parsed.Usage.OutputTokens,
+			"total_tokens":      parsed.Usage.InputTokens + parsed.Usage.OutputTokens,
+		},
+	}
+
+	out, err := json.Marshal(translated)
+	if err != nil {
+		return fmt.Errorf("marshal translated response: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(out))
+	resp.ContentLength = int64(len(out))
+	resp.Header.Set("Content-Length", fmt.Sprintf("%d", len(out)))
+	return nil
+}
+
+// anthropicStreamEvent is the subset of fields an Anthropic SSE "data:"
+// payload carries that anthropicStreamTranslator cares about.
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+}
+
+// anthropicStreamTranslator rewrites Anthropic's event/data SSE framing
+// (content_block_delta, message_delta, message_stop) into OpenAI-format
+// chat.completion.chunk SSE events as the body is read.
+type anthropicStreamTranslator struct {
+	model string
+	buf   []byte
+}
+
+// NewStreamTranslator returns a fresh translator for one streaming
+// request, since Anthropic's "event:"/"data:" lines can land split across
+// a read that doesn't line up with an event boundary.
+func (a *AnthropicProvider) NewStreamTranslator(model string) StreamChunkTranslator {
+	return &anthropicStreamTranslator{model: model}
+}
+
+func (t *anthropicStreamTranslator) Translate(chunk []byte) []byte {
+	t.buf = append(t.buf, chunk...)
+
+	var out bytes.Buffer
+	for {
+		i := bytes.IndexByte(t.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimRight(t.buf[:i], "\r")
+		t.buf = t.buf[i+1:]
+
+		if !bytes.HasPrefix(line, []byte("data: ")) {
+			continue
+		}
+
+		var event anthropicStreamEvent
+		if err := json.Unmarshal(bytes.TrimPrefix(line, []byte("data: ")), &event); err != nil {
+			continue
+		}
+
+		switch event.Type {
+		case "content_block_delta":
+			if event.Delta.Text != "" {
+				out.Write(openAIStreamEvent(t.model, event.Delta.Text, ""))
+			}
+		case "message_delta":
+			if event.Delta.StopReason != "" {
+				out.Write(openAIStreamEvent(t.model, "", event.Delta.StopReason))
+			}
+		case "message_stop":
+			out.Write(openAIStreamDone())
+		}
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// CohereProvider speaks Cohere's chat schema: a single `message` string
+// for the latest turn plus a `chat_history` array for everything before
+// it, rather than OpenAI's flat `messages` array.
+type CohereProvider struct {
+	endpoint string
+	models   []string
+}
+
+// NewCohereProvider creates a CohereProvider. models is registered as a
+// prefix match in ServiceResolver, so "command-" routes every command-*
+// model here.
+func NewCohereProvider() *CohereProvider {
+	return &CohereProvider{
+		endpoint: "https://api.cohere.ai",
+		models:   []string{"command-"},
+	}
+}
+
+func (c *CohereProvider) GetEndpoint() string { return c.endpoint }
+
+func (c *CohereProvider) GetModels() []string { return c.models }
+
+func (c *CohereProvider) CountTokens(req *http.Request, model string) (int, error) {
+	content, err := parseMessagesContent(req)
+	if err != nil {
+		return 0, err
+	}
+	return approximateTokenCount(strings.Join(content, " ")), nil
+}
+
+type cohereChatTurn struct {
+	Role    string `json:"role"`
+	Message string `json:"message"`
+}
+
+type cohereRequest struct {
+	Model       string           `json:"model"`
+	Message     string           `json:"message"`
+	ChatHistory []cohereChatTurn `json:"chat_history,omitempty"`
+	Preamble    string           `json:"preamble,omitempty"`
+	Stream      bool             `json:"stream,omitempty"`
+}
+
+// cohereRole maps an OpenAI message role onto Cohere's chat_history roles.
+func cohereRole(role string) string {
+	if role == "assistant" {
+		return "CHATBOT"
+	}
+	return "USER"
+}
+
+// TranslateRequest rewrites an OpenAI-format chat completion body into
+// Cohere's {message, chat_history} shape and points the request at
+// /v1/chat.
+func (c *CohereProvider) TranslateRequest(req *http.Request) error {
+	var payload struct {
+		Model    string `json:"model"`
+		Stream   bool   `json:"stream,omitempty"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Errorf("decode OpenAI-format request: %w", err)
+	}
+
+	// Cohere's required `message` field holds the latest user turn, not
+	// simply the last message in the conversation - a request that ends on
+	// a system or assistant message (e.g. a trailing system instruction)
+	// would otherwise leave it empty or mis-populated. Fall back to the
+	// last non-system message if there's no user turn at all.
+	messageIdx := -1
+	for i, m := range payload.Messages {
+		if m.Role == "user" {
+			messageIdx = i
+		}
+	}
+	if messageIdx == -1 {
+		for i, m := range payload.Messages {
+			if m.Role != "system" {
+				messageIdx = i
+			}
+		}
+	}
+
+	translated := cohereRequest{Model: payload.Model, Stream: payload.Stream}
+	for i, m := range payload.Messages {
+		switch {
+		case m.Role == "system":
+			translated.Preamble = m.Content
+		case i == messageIdx:
+			translated.Message = m.Content
+		default:
+			translated.ChatHistory = append(translated.ChatHistory, cohereChatTurn{
+				Role:    cohereRole(m.Role),
+				Message: m.Content,
+			})
+		}
+	}
+
+	out, err := json.Marshal(translated)
+	if err != nil {
+		return err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(out))
+	req.ContentLength = int64(len(out))
+	req.URL.Path = "/v1/chat"
+	req.Header.Set("Content-Type", "application/json")
+	return nil
+}
+
+type cohereResponse struct {
+	Text         string `json:"text"`
+	FinishReason string `json:"finish_reason"`
+	Meta         struct {
+		BilledUnits struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+// TranslateResponse rewrites a Cohere chat response back into OpenAI's
+// chat completion shape.
+func (c *CohereProvider) TranslateResponse(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	var parsed cohereResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return nil
+	}
+
+	translated := map[string]interface{}{
+		"object": "chat.completion",
+		"choices": []map[string]interface{}{
+			{
+				"index": 0,
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": parsed.Text,
+				},
+				"finish_reason": parsed.FinishReason,
+			},
+		},
+		"usage": map[string]int{
+			"prompt_tokens":     parsed.Meta.BilledUnits.InputTokens,
+			"completion_tokens": parsed.Meta.BilledUnits.OutputTokens,
+			"total_tokens":      parsed.Meta.BilledUnits.InputTokens + parsed.Meta.BilledUnits.OutputTokens,
+		},
+	}
+
+	out, err := json.Marshal(translated)
+	if err != nil {
+		return fmt.Errorf("marshal translated response: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(out))
+	resp.ContentLength = int64(len(out))
+	resp.Header.Set("Content-Length", fmt.Sprintf("%d", len(out)))
+	return nil
+}
+
+// cohereStreamEvent is the subset of fields one line of Cohere's
+// newline-delimited streaming response carries that
+// cohereStreamTranslator cares about.
+type cohereStreamEvent struct {
+	EventType    string `json:"event_type"`
+	Text         string `json:"text"`
+	IsFinished   bool   `json:"is_finished"`
+	FinishReason string `json:"finish_reason"`
+}
+
+// cohereStreamTranslator rewrites Cohere's newline-delimited JSON stream
+// events into OpenAI-format chat.completion.chunk SSE events as the body
+// is read.
+type cohereStreamTranslator struct {
+	model string
+	buf   []byte
+}
+
+// NewStreamTranslator returns a fresh translator for one streaming
+// request, since a JSON line can land split across reads that don't line
+// up with Cohere's own newline boundaries.
+func (c *CohereProvider) NewStreamTranslator(model string) StreamChunkTranslator {
+	return &cohereStreamTranslator{model: model}
+}
+
+func (t *cohereStreamTranslator) Translate(chunk []byte) []byte {
+	t.buf = append(t.buf, chunk...)
+
+	var out bytes.Buffer
+	for {
+		i := bytes.IndexByte(t.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimRight(t.buf[:i], "\r")
+		t.buf = t.buf[i+1:]
+		if len(line) == 0 {
+			continue
+		}
+
+		var event cohereStreamEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			continue
+		}
+
+		switch {
+		case event.EventType == "text-generation":
+			out.Write(openAIStreamEvent(t.model, event.Text, ""))
+		case event.IsFinished:
+			reason := event.FinishReason
+			if reason == "" {
+				reason = "COMPLETE"
+			}
+			out.Write(openAIStreamEvent(t.model, "", reason))
+			out.Write(openAIStreamDone())
+		}
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// bearerToken extracts the token from an inbound "Authorization: Bearer
+// <token>" header, so a provider's TranslateRequest can re-authenticate
+// to its own upstream (x-api-key, x-goog-api-key, ...) instead of
+// forwarding a header the upstream doesn't understand.
+func bearerToken(req *http.Request) string {
+	auth := req.Header.Get("Authorization")
+	return strings.TrimPrefix(auth, "Bearer ")
+}
+
+// approximateTokenCount estimates a token count for providers that don't
+// expose a tokenizer compatible with tiktoken. It uses the widely cited
+// ~4-characters-per-token heuristic, which is close enough for cache keys
+// and rate-limit bookkeeping even though it isn't exact.
+func approximateTokenCount(text string) int {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0
+	}
+	return (len(text) + 3) / 4
+}
+
+// openAIStreamEvent marshals one OpenAI-format chat.completion.chunk SSE
+// event carrying a content delta, a finish reason, or both, so a
+// StreamChunkTranslator doesn't have to hand-build the envelope.
+func openAIStreamEvent(model, content, finishReason string) []byte {
+	delta := map[string]string{}
+	if content != "" {
+		delta["content"] = content
+	}
+
+	choice := map[string]interface{}{
+		"index": 0,
+		"delta": delta,
+	}
+	if finishReason != "" {
+		choice["finish_reason"] = finishReason
+	} else {
+		choice["finish_reason"] = nil
+	}
+
+	out, _ := json.Marshal(map[string]interface{}{
+		"object":  "chat.completion.chunk",
+		"model":   model,
+		"choices": []map[string]interface{}{choice},
+	})
+
+	var event bytes.Buffer
+	event.WriteString("data: ")
+	event.Write(out)
+	event.WriteString("\n\n")
+	return event.Bytes()
+}
+
+// openAIStreamDone is the terminal SSE event OpenAI clients expect at the
+// end of a stream.
+func openAIStreamDone() []byte {
+	return []byte("data: [DONE]\n\n")
+}
@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// GeminiProvider speaks Google's generativelanguage `contents`/`parts`
+// schema rather than OpenAI's `messages`/`content`.
+type GeminiProvider struct {
+	endpoint string
+	models   []string
+}
+
+// NewGeminiProvider creates a GeminiProvider. models is registered as a
+// prefix match in ServiceResolver, so "gemini-" routes every gemini-*
+// model here.
+func NewGeminiProvider() *GeminiProvider {
+	return &GeminiProvider{
+		endpoint: "https://generativelanguage.googleapis.com",
+		models:   []string{"gemini-"},
+	}
+}
+
+func (g *GeminiProvider) GetEndpoint() string { return g.endpoint }
+
+func (g *GeminiProvider) GetModels() []string { return g.models }
+
+func (g *GeminiProvider) CountTokens(req *http.Request, model string) (int, error) {
+	content, err := parseMessagesContent(req)
+	if err != nil {
+		return 0, err
+	}
+	return approximateTokenCount(strings.Join(content, " ")), nil
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiRequest struct {
+	Contents          []geminiContent `json:"contents"`
+	SystemInstruction *geminiContent  `json:"systemInstruction,omitempty"`
+}
+
+// geminiRole maps an OpenAI message role onto Gemini's two-role model.
+func geminiRole(role string) string {
+	if role == "assistant" {
+		return "model"
+	}
+	return "user"
+}
+
+// TranslateRequest rewrites an OpenAI-format chat completion body into
+// Gemini's {contents:[{role, parts:[{text}]}]} shape, points the request
+// at /v1beta/models/{model}:generateContent (or :streamGenerateContent
+// with ?alt=sse when streaming, so the response is real line-delimited
+// SSE instead of a single JSON array), and swaps the inbound bearer token
+// for the x-goog-api-key header Gemini expects.
+func (g *GeminiProvider) TranslateRequest(req *http.Request) error {
+	var payload struct {
+		Model    string `json:"model"`
+		Stream   bool   `json:"stream,omitempty"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Errorf("decode OpenAI-format request: %w", err)
+	}
+
+	var translated geminiRequest
+	for _, m := range payload.Messages {
+		content := geminiContent{Parts: []geminiPart{{Text: m.Content}}}
+		if m.Role == "system" {
+			translated.SystemInstruction = &content
+			continue
+		}
+		content.Role = geminiRole(m.Role)
+		translated.Contents = append(translated.Contents, content)
+	}
+
+	out, err := json.Marshal(translated)
+	if err != nil {
+		return err
+	}
+
+	method := "generateContent"
+	if payload.Stream {
+		method = "streamGenerateContent"
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(out))
+	req.ContentLength = int64(len(out))
+	req.URL.Path = fmt.Sprintf("/v1beta/models/%s:%s", payload.Model, method)
+	if payload.Stream {
+		// Without alt=sse, streamGenerateContent replies with a single
+		// JSON array under Content-Type: application/json instead of
+		// real SSE, which geminiStreamTranslator can't parse line-by-line.
+		req.URL.RawQuery = "alt=sse"
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Gemini authenticates via x-goog-api-key, not a bearer token.
+	req.Header.Set("x-goog-api-key", bearerToken(req))
+	req.Header.Del("Authorization")
+	return nil
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// TranslateResponse rewrites a Gemini generateContent response back into
+// OpenAI's chat completion shape.
+func (g *GeminiProvider) TranslateResponse(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	var parsed geminiResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return nil
+	}
+
+	var text strings.Builder
+	var finishReason string
+	if len(parsed.Candidates) > 0 {
+		finishReason = parsed.Candidates[0].FinishReason
+		for _, part := range parsed.Candidates[0].Content.Parts {
+			text.WriteString(part.Text)
+		}
+	}
+
+	translated := map[string]interface{}{
+		"object": "chat.completion",
+		"choices": []map[string]interface{}{
+			{
+				"index": 0,
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": text.String(),
+				},
+				"finish_reason": finishReason,
+			},
+		},
+		"usage": map[string]int{
+			"prompt_tokens":     parsed.UsageMetadata.PromptTokenCount,
+			"completion_tokens": parsed.UsageMetadata.CandidatesTokenCount,
+			"total_tokens":      parsed.UsageMetadata.TotalTokenCount,
+		},
+	}
+
+	out, err := json.Marshal(translated)
+	if err != nil {
+		return fmt.Errorf("marshal translated response: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(out))
+	resp.ContentLength = int64(len(out))
+	resp.Header.Set("Content-Length", fmt.Sprintf("%d", len(out)))
+	return nil
+}
+
+// geminiStreamTranslator rewrites Gemini's alt=sse event stream into
+// OpenAI-format chat.completion.chunk SSE events as the body is read.
+type geminiStreamTranslator struct {
+	model string
+	buf   []byte
+}
+
+// NewStreamTranslator returns a fresh translator for one streaming
+// request, since a "data: " line can land split across reads that don't
+// line up with an event boundary.
+func (g *GeminiProvider) NewStreamTranslator(model string) StreamChunkTranslator {
+	return &geminiStreamTranslator{model: model}
+}
+
+func (t *geminiStreamTranslator) Translate(chunk []byte) []byte {
+	t.buf = append(t.buf, chunk...)
+
+	var out bytes.Buffer
+	for {
+		i := bytes.IndexByte(t.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimRight(t.buf[:i], "\r")
+		t.buf = t.buf[i+1:]
+
+		if !bytes.HasPrefix(line, []byte("data: ")) {
+			continue
+		}
+
+		var parsed geminiResponse
+		if err := json.Unmarshal(bytes.TrimPrefix(line, []byte("data: ")), &parsed); err != nil {
+			continue
+		}
+		if len(parsed.Candidates) == 0 {
+			continue
+		}
+
+		candidate := parsed.Candidates[0]
+		var text strings.Builder
+		for _, part := range candidate.Content.Parts {
+			text.WriteString(part.Text)
+		}
+
+		out.Write(openAIStreamEvent(t.model, text.String(), ""))
+		if candidate.FinishReason != "" {
+			out.Write(openAIStreamEvent(t.model, "", candidate.FinishReason))
+			out.Write(openAIStreamDone())
+		}
+	}
+	return out.Bytes()
+}
@@ -2,8 +2,41 @@ package provider
 
 import "net/http"
 
+// IModelProvider describes an upstream model API the proxy can route to.
+// Clients always speak OpenAI's chat completion schema; TranslateRequest
+// and TranslateResponse let each provider rewrite that schema to and from
+// its own wire format so the rest of the proxy (caching, rate limiting,
+// audit logging) only ever has to deal with one shape.
 type IModelProvider interface {
 	GetEndpoint() string
 	GetModels() []string
 	CountTokens(req *http.Request, model string) (int, error)
+	// TranslateRequest rewrites an OpenAI-format request body (and path)
+	// in place into the provider's native schema.
+	TranslateRequest(req *http.Request) error
+	// TranslateResponse rewrites a provider-native response body in place
+	// back into OpenAI's chat completion schema.
+	TranslateResponse(resp *http.Response) error
+}
+
+// StreamChunkTranslator rewrites one chunk of a provider-native streaming
+// body, as it's read off the upstream connection, into the equivalent
+// OpenAI chat.completion.chunk SSE bytes. A chunk boundary rarely lines up
+// with an event boundary, so a single call may return fewer bytes than a
+// whole translated event (while a partial line is buffered internally) or
+// more than one.
+type StreamChunkTranslator interface {
+	Translate(chunk []byte) []byte
+}
+
+// StreamTranslator is implemented by providers whose streaming wire format
+// isn't already OpenAI's SSE chat.completion.chunk shape (Anthropic's
+// event/data framing, Gemini's alt=sse events, Cohere/Ollama's
+// newline-delimited JSON). ServiceResolver checks for it via a type
+// assertion, since OpenAI itself needs no translation.
+type StreamTranslator interface {
+	// NewStreamTranslator returns a fresh translator for one streaming
+	// request. Translation requires buffering state (a partial line, an
+	// in-progress event) across calls, so each request needs its own.
+	NewStreamTranslator(model string) StreamChunkTranslator
 }
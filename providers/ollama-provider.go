@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OllamaProvider talks to a local Ollama instance. Ollama's /api/chat
+// already accepts an OpenAI-shaped messages array, so translation mostly
+// just strips the "ollama/" prefix and rewrites the response envelope.
+type OllamaProvider struct {
+	endpoint string
+	models   []string
+}
+
+// NewOllamaProvider creates an OllamaProvider pointed at a local Ollama
+// server. models is registered as a prefix match in ServiceResolver, so
+// "ollama/" routes every ollama/* model here.
+func NewOllamaProvider() *OllamaProvider {
+	return &OllamaProvider{
+		endpoint: "http://localhost:11434",
+		models:   []string{"ollama/"},
+	}
+}
+
+func (o *OllamaProvider) GetEndpoint() string { return o.endpoint }
+
+func (o *OllamaProvider) GetModels() []string { return o.models }
+
+func (o *OllamaProvider) CountTokens(req *http.Request, model string) (int, error) {
+	content, err := parseMessagesContent(req)
+	if err != nil {
+		return 0, err
+	}
+	return approximateTokenCount(strings.Join(content, " ")), nil
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	// Stream is always sent: Ollama streams when the field is absent.
+	Stream bool `json:"stream"`
+}
+
+// TranslateRequest strips the "ollama/" routing prefix from the model name
+// and points the request at /api/chat.
+func (o *OllamaProvider) TranslateRequest(req *http.Request) error {
+	var payload struct {
+		Model    string          `json:"model"`
+		Messages []ollamaMessage `json:"messages"`
+		Stream   bool            `json:"stream,omitempty"`
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Errorf("decode OpenAI-format request: %w", err)
+	}
+
+	translated := ollamaRequest{
+		Model:    strings.TrimPrefix(payload.Model, "ollama/"),
+		Messages: payload.Messages,
+		Stream:   payload.Stream,
+	}
+
+	out, err := json.Marshal(translated)
+	if err != nil {
+		return err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(out))
+	req.ContentLength = int64(len(out))
+	req.URL.Path = "/api/chat"
+	req.Header.Set("Content-Type", "application/json")
+	return nil
+}
+
+type ollamaResponse struct {
+	Model   string        `json:"model"`
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+// TranslateResponse rewrites an Ollama /api/chat response back into
+// OpenAI's chat completion shape.
+func (o *OllamaProvider) TranslateResponse(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	var parsed ollamaResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return nil
+	}
+
+	finishReason := "stop"
+	if !parsed.Done {
+		finishReason = "length"
+	}
+
+	translated := map[string]interface{}{
+		"object": "chat.completion",
+		"model":  parsed.Model,
+		"choices": []map[string]interface{}{
+			{
+				"index": 0,
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": parsed.Message.Content,
+				},
+				"finish_reason": finishReason,
+			},
+		},
+		"usage": map[string]int{
+			"prompt_tokens":     parsed.PromptEvalCount,
+			"completion_tokens": parsed.EvalCount,
+			"total_tokens":      parsed.PromptEvalCount + parsed.EvalCount,
+		},
+	}
+
+	out, err := json.Marshal(translated)
+	if err != nil {
+		return fmt.Errorf("marshal translated response: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(out))
+	resp.ContentLength = int64(len(out))
+	resp.Header.Set("Content-Length", fmt.Sprintf("%d", len(out)))
+	return nil
+}
+
+// ollamaStreamTranslator rewrites Ollama's newline-delimited /api/chat
+// stream into OpenAI-format chat.completion.chunk SSE events as the body
+// is read.
+type ollamaStreamTranslator struct {
+	model string
+	buf   []byte
+}
+
+// NewStreamTranslator returns a fresh translator for one streaming
+// request, since a JSON line can land split across reads that don't line
+// up with Ollama's own newline boundaries.
+func (o *OllamaProvider) NewStreamTranslator(model string) StreamChunkTranslator {
+	return &ollamaStreamTranslator{model: model}
+}
+
+func (t *ollamaStreamTranslator) Translate(chunk []byte) []byte {
+	t.buf = append(t.buf, chunk...)
+
+	var out bytes.Buffer
+	for {
+		i := bytes.IndexByte(t.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimRight(t.buf[:i], "\r")
+		t.buf = t.buf[i+1:]
+		if len(line) == 0 {
+			continue
+		}
+
+		var parsed ollamaResponse
+		if err := json.Unmarshal(line, &parsed); err != nil {
+			continue
+		}
+
+		if parsed.Message.Content != "" {
+			out.Write(openAIStreamEvent(t.model, parsed.Message.Content, ""))
+		}
+		if parsed.Done {
+			out.Write(openAIStreamEvent(t.model, "", "stop"))
+			out.Write(openAIStreamDone())
+		}
+	}
+	return out.Bytes()
+}
@@ -73,6 +73,14 @@ func (o *OpenAIProvider) CountTokens(req *http.Request, model string) (int, erro
 	return len(token), nil
 }
 
+// TranslateRequest is a no-op: clients already send OpenAI's chat
+// completion schema, which is what api.openai.com expects.
+func (o *OpenAIProvider) TranslateRequest(req *http.Request) error { return nil }
+
+// TranslateResponse is a no-op: api.openai.com already responds in the
+// proxy's canonical (OpenAI) schema.
+func (o *OpenAIProvider) TranslateResponse(resp *http.Response) error { return nil }
+
 func NewOpenAIProvider() *OpenAIProvider {
 	return &OpenAIProvider{
 		endpoint: "https://api.openai.com",
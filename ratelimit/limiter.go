@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+)
+
+// Store persists token-bucket levels so budgets can be shared across
+// proxy replicas (Redis) or kept purely in-process (memory).
+type Store interface {
+	// Take attempts to consume cost units from the bucket named key,
+	// which refills continuously up to capacity at refillPerSec units per
+	// second. It reports whether the bucket had enough room, and if not,
+	// how long until it will.
+	Take(key string, cost, capacity, refillPerSec float64) (allowed bool, retryAfter time.Duration)
+	// Add debits delta units from a bucket (or, with a negative delta,
+	// credits them back) without a capacity check, used to charge actual
+	// post-hoc usage such as completion tokens that weren't known at
+	// request time.
+	Add(key string, delta, capacity, refillPerSec float64)
+	// Level reports a bucket's level as stored by its last Take or Add, if
+	// it has ever been used. Refill accrued since then is not included.
+	Level(key string) (level float64, ok bool)
+}
+
+// Limit describes one token bucket: Capacity is the maximum burst size and
+// Window is how long a full bucket takes to drain-and-refill. A zero
+// Capacity disables the budget entirely.
+type Limit struct {
+	Capacity float64
+	Window   time.Duration
+}
+
+func (l Limit) refillPerSec() float64 {
+	if l.Window <= 0 {
+		return 0
+	}
+	return l.Capacity / l.Window.Seconds()
+}
+
+// Config holds the three budgets Limiter enforces per key.
+type Config struct {
+	RequestsPerMinute  Limit
+	PromptTokensPerMin Limit
+	TotalTokensPerDay  Limit
+}
+
+// LimitExceededError is returned when a request would exceed one of the
+// configured budgets.
+type LimitExceededError struct {
+	Budget     string
+	RetryAfter time.Duration
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s budget exceeded, retry after %s", e.Budget, e.RetryAfter)
+}
+
+// Limiter enforces per-key request-rate, prompt-token, and total-token
+// budgets using token buckets backed by a pluggable Store.
+type Limiter struct {
+	store  Store
+	config Config
+}
+
+// NewLimiter creates a Limiter. A Limit with a zero Capacity is treated as
+// unlimited and skipped.
+func NewLimiter(store Store, config Config) *Limiter {
+	return &Limiter{store: store, config: config}
+}
+
+// CheckRequest consumes one request and promptTokens prompt tokens from
+// key's buckets, in that order. It returns a *LimitExceededError naming
+// whichever budget ran out first.
+func (l *Limiter) CheckRequest(key string, promptTokens int) error {
+	if l.config.RequestsPerMinute.Capacity > 0 {
+		if ok, retryAfter := l.store.Take(key+":rpm", 1, l.config.RequestsPerMinute.Capacity, l.config.RequestsPerMinute.refillPerSec()); !ok {
+			return &LimitExceededError{Budget: "requests-per-minute", RetryAfter: retryAfter}
+		}
+	}
+	if l.config.PromptTokensPerMin.Capacity > 0 {
+		if ok, retryAfter := l.store.Take(key+":ptm", float64(promptTokens), l.config.PromptTokensPerMin.Capacity, l.config.PromptTokensPerMin.refillPerSec()); !ok {
+			return &LimitExceededError{Budget: "prompt-tokens-per-minute", RetryAfter: retryAfter}
+		}
+	}
+	if l.config.TotalTokensPerDay.Capacity > 0 {
+		if ok, retryAfter := l.store.Take(key+":ttd", float64(promptTokens), l.config.TotalTokensPerDay.Capacity, l.config.TotalTokensPerDay.refillPerSec()); !ok {
+			return &LimitExceededError{Budget: "total-tokens-per-day", RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// ChargeCompletionTokens debits additional completion tokens, discovered
+// only after the response came back, against key's total-tokens-per-day
+// budget. It does not touch prompt-tokens-per-minute: that budget is
+// charged in CheckRequest against actual prompt tokens only, and
+// completion tokens have nothing to do with it.
+func (l *Limiter) ChargeCompletionTokens(key string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	if l.config.TotalTokensPerDay.Capacity > 0 {
+		l.store.Add(key+":ttd", float64(tokens), l.config.TotalTokensPerDay.Capacity, l.config.TotalTokensPerDay.refillPerSec())
+	}
+}
+
+// Usage reports how much of each budget key has used, for the
+// GET /_proxy/usage endpoint. It is computed from Store.Level, so it
+// reflects each bucket as of its last Take or Add, not refill since.
+type Usage struct {
+	RequestsUsed     float64 `json:"requests_used,omitempty"`
+	PromptTokensUsed float64 `json:"prompt_tokens_used,omitempty"`
+	TotalTokensUsed  float64 `json:"total_tokens_used,omitempty"`
+}
+
+func (l *Limiter) Usage(key string) Usage {
+	var usage Usage
+	if level, ok := l.store.Level(key + ":rpm"); ok {
+		usage.RequestsUsed = l.config.RequestsPerMinute.Capacity - level
+	}
+	if level, ok := l.store.Level(key + ":ptm"); ok {
+		usage.PromptTokensUsed = l.config.PromptTokensPerMin.Capacity - level
+	}
+	if level, ok := l.store.Level(key + ":ttd"); ok {
+		usage.TotalTokensUsed = l.config.TotalTokensPerDay.Capacity - level
+	}
+	return usage
+}
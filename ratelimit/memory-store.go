@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type bucketState struct {
+	level      float64
+	lastRefill time.Time
+}
+
+// MemoryStore is an in-process token-bucket Store. Counters are lost on
+// restart and aren't shared across replicas; use RedisStore for that.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucketState
+}
+
+// NewMemoryStore creates a MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucketState)}
+}
+
+// refill tops b up to the current time. Caller must hold s.mu. A bucket
+// starts full, since the first request against a brand-new key shouldn't
+// be rejected for having "zero" budget.
+func (s *MemoryStore) refill(b *bucketState, capacity, refillPerSec float64, now time.Time) {
+	if b.lastRefill.IsZero() {
+		b.level = capacity
+		b.lastRefill = now
+		return
+	}
+	if elapsed := now.Sub(b.lastRefill).Seconds(); elapsed > 0 {
+		b.level += elapsed * refillPerSec
+		if b.level > capacity {
+			b.level = capacity
+		}
+		b.lastRefill = now
+	}
+}
+
+func (s *MemoryStore) bucket(key string) *bucketState {
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucketState{}
+		s.buckets[key] = b
+	}
+	return b
+}
+
+func (s *MemoryStore) Take(key string, cost, capacity, refillPerSec float64) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.bucket(key)
+	s.refill(b, capacity, refillPerSec, time.Now())
+
+	if b.level >= cost {
+		b.level -= cost
+		return true, 0
+	}
+
+	var retryAfter time.Duration
+	if refillPerSec > 0 {
+		retryAfter = time.Duration((cost - b.level) / refillPerSec * float64(time.Second))
+	}
+	return false, retryAfter
+}
+
+func (s *MemoryStore) Add(key string, delta, capacity, refillPerSec float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.bucket(key)
+	s.refill(b, capacity, refillPerSec, time.Now())
+	b.level -= delta
+}
+
+func (s *MemoryStore) Level(key string) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		return 0, false
+	}
+	return b.level, true
+}
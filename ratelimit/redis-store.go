@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// RedisStore is a Store backed by Redis so rate-limit counters persist
+// across restarts and are shared across proxy replicas, instead of living
+// only in one process's memory.
+type RedisStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisStore creates a RedisStore using client, namespacing every key
+// under keyPrefix.
+func NewRedisStore(client *redis.Client, keyPrefix string) *RedisStore {
+	return &RedisStore{client: client, keyPrefix: keyPrefix}
+}
+
+func (s *RedisStore) levelKey(key string) string  { return s.keyPrefix + key + ":level" }
+func (s *RedisStore) refillKey(key string) string { return s.keyPrefix + key + ":refilled_at" }
+
+// takeScript refills a bucket based on elapsed time and, if it holds
+// enough for cost, debits it, all inside one server-side Lua script. A
+// plain GET-then-SET from the Go client would let two replicas both read
+// the same level and both succeed, over-admitting past the shared budget;
+// running the whole read-modify-write as a script makes it atomic from
+// Redis's point of view.
+var takeScript = redis.NewScript(`
+local level_key = KEYS[1]
+local refill_key = KEYS[2]
+local cost = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local refill_per_sec = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+
+local level = tonumber(redis.call("GET", level_key))
+local refilled_at = tonumber(redis.call("GET", refill_key))
+
+if level == nil or refilled_at == nil then
+	level = capacity
+else
+	level = level + (now - refilled_at) * refill_per_sec
+	if level > capacity then
+		level = capacity
+	end
+end
+
+local allowed = 0
+if level >= cost then
+	level = level - cost
+	allowed = 1
+end
+
+redis.call("SET", level_key, tostring(level))
+redis.call("SET", refill_key, tostring(now))
+
+return {allowed, tostring(level)}
+`)
+
+// addScript is takeScript's unconditional counterpart: it refills, then
+// debits delta without a capacity check, used to charge actual completion
+// token usage discovered after the fact.
+var addScript = redis.NewScript(`
+local level_key = KEYS[1]
+local refill_key = KEYS[2]
+local delta = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local refill_per_sec = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+
+local level = tonumber(redis.call("GET", level_key))
+local refilled_at = tonumber(redis.call("GET", refill_key))
+
+if level == nil or refilled_at == nil then
+	level = capacity
+else
+	level = level + (now - refilled_at) * refill_per_sec
+	if level > capacity then
+		level = capacity
+	end
+end
+
+level = level - delta
+
+redis.call("SET", level_key, tostring(level))
+redis.call("SET", refill_key, tostring(now))
+
+return tostring(level)
+`)
+
+func (s *RedisStore) Take(key string, cost, capacity, refillPerSec float64) (bool, time.Duration) {
+	ctx := context.Background()
+	res, err := takeScript.Run(ctx, s.client, []string{s.levelKey(key), s.refillKey(key)}, cost, capacity, refillPerSec, time.Now().Unix()).Slice()
+	if err != nil {
+		// Fail open, matching MemoryStore's behavior for a brand-new key:
+		// a bucket we can't reach shouldn't block every request.
+		return true, 0
+	}
+
+	allowed, _ := res[0].(int64)
+	level, _ := strconv.ParseFloat(res[1].(string), 64)
+
+	if allowed == 1 {
+		return true, 0
+	}
+
+	var retryAfter time.Duration
+	if refillPerSec > 0 {
+		retryAfter = time.Duration((cost - level) / refillPerSec * float64(time.Second))
+	}
+	return false, retryAfter
+}
+
+func (s *RedisStore) Add(key string, delta, capacity, refillPerSec float64) {
+	ctx := context.Background()
+	addScript.Run(ctx, s.client, []string{s.levelKey(key), s.refillKey(key)}, delta, capacity, refillPerSec, time.Now().Unix())
+}
+
+func (s *RedisStore) Level(key string) (float64, bool) {
+	level, err := s.client.Get(context.Background(), s.levelKey(key)).Float64()
+	if err != nil {
+		return 0, false
+	}
+	return level, true
+}